@@ -0,0 +1,236 @@
+package genji
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asdine/genji/document"
+)
+
+// defaultBatchChunkSize is the number of documents sent to the engine in a
+// single INSERT/UPDATE statement when no WithChunkSize option is given.
+const defaultBatchChunkSize = 100
+
+type batchConfig struct {
+	chunkSize int
+}
+
+var defaultBatchConfig = batchConfig{
+	chunkSize: defaultBatchChunkSize,
+}
+
+// BatchOption configures the chunking behavior of InsertBatch and
+// UpdateBatch.
+type BatchOption func(*batchConfig)
+
+// WithChunkSize sets the number of documents streamed per INSERT/UPDATE
+// statement. n must be at least 1.
+func WithChunkSize(n int) BatchOption {
+	return func(cfg *batchConfig) {
+		if n > 0 {
+			cfg.chunkSize = n
+		}
+	}
+}
+
+// RowError reports the document, by index in the original batch, that
+// failed during InsertBatch or UpdateBatch, alongside the underlying error.
+type RowError struct {
+	Index int
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// InsertBatch inserts docs into table, streaming them to the engine in
+// chunks of a configurable size and running the whole batch in a single
+// retryable transaction.
+func (db *DB) InsertBatch(table string, docs []document.Document, opts ...BatchOption) error {
+	return db.RunInTransaction(func(tx *Tx) error {
+		return tx.InsertBatch(table, docs, opts...)
+	})
+}
+
+// InsertBatch inserts docs into table within tx, as one multi-row
+// "INSERT INTO table VALUES (...), (...), ..." statement per chunk. All
+// documents of a chunk must share the same set of fields, taken from the
+// chunk's first document; a later document of the chunk with a missing or
+// extra field is rejected as a *RowError rather than silently dropped.
+// Documents in different chunks are not compared with each other.
+func (tx *Tx) InsertBatch(table string, docs []document.Document, opts ...BatchOption) error {
+	cfg := defaultBatchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	for start := 0; start < len(docs); start += cfg.chunkSize {
+		end := start + cfg.chunkSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		if err := tx.insertChunk(table, docs[start:end]); err != nil {
+			if rerr, ok := err.(*RowError); ok {
+				rerr.Index += start
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tx *Tx) insertChunk(table string, docs []document.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	fields, err := documentFields(docs[0])
+	if err != nil {
+		return &RowError{Index: 0, Err: err}
+	}
+
+	var q strings.Builder
+	fmt.Fprintf(&q, "INSERT INTO %s (%s) VALUES ", table, strings.Join(fields, ", "))
+
+	var args []interface{}
+	for i, d := range docs {
+		if i > 0 {
+			q.WriteString(", ")
+
+			if err := checkSameFields(fields, d); err != nil {
+				return &RowError{Index: i, Err: err}
+			}
+		}
+
+		q.WriteByte('(')
+		for j, f := range fields {
+			if j > 0 {
+				q.WriteString(", ")
+			}
+			q.WriteByte('?')
+
+			v, err := d.GetByField(f)
+			if err != nil {
+				return &RowError{Index: i, Err: err}
+			}
+			args = append(args, v.V)
+		}
+		q.WriteByte(')')
+	}
+
+	return tx.Exec(q.String(), args...)
+}
+
+func documentFields(d document.Document) ([]string, error) {
+	var fields []string
+	err := d.Iterate(func(field string, v document.Value) error {
+		fields = append(fields, field)
+		return nil
+	})
+	return fields, err
+}
+
+// checkSameFields reports an error if d's set of fields isn't exactly
+// fields, so a document with a missing or extra field fails fast instead of
+// silently dropping the extra field or surfacing an opaque GetByField error.
+func checkSameFields(fields []string, d document.Document) error {
+	got, err := documentFields(d)
+	if err != nil {
+		return err
+	}
+
+	if len(got) != len(fields) {
+		return fmt.Errorf("document has %d fields, want %d", len(got), len(fields))
+	}
+
+	want := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		want[f] = struct{}{}
+	}
+
+	for _, f := range got {
+		if _, ok := want[f]; !ok {
+			return fmt.Errorf("unexpected field %q", f)
+		}
+	}
+
+	return nil
+}
+
+// UpdateBatch updates, within table, each document in docs keyed by its
+// primary key, running the whole batch in a single retryable transaction.
+func (db *DB) UpdateBatch(table string, docs []document.Document, opts ...BatchOption) error {
+	return db.RunInTransaction(func(tx *Tx) error {
+		return tx.UpdateBatch(table, docs, opts...)
+	})
+}
+
+// UpdateBatch is like DB.UpdateBatch but runs within tx. Each document is
+// looked up by its table's actual primary key field and every other field
+// is set in place. The first row that fails is returned as a *RowError,
+// aborting the rest of the batch, the same way InsertBatch does.
+func (tx *Tx) UpdateBatch(table string, docs []document.Document, opts ...BatchOption) error {
+	cfg := defaultBatchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tb, err := tx.GetTable(table)
+	if err != nil {
+		return err
+	}
+
+	pkName, err := tb.PrimaryKeyName()
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(docs); start += cfg.chunkSize {
+		end := start + cfg.chunkSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		for i, d := range docs[start:end] {
+			if err := tx.updateOne(table, pkName, d); err != nil {
+				return &RowError{Index: start + i, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tx *Tx) updateOne(table, pkName string, d document.Document) error {
+	pk, err := d.GetByField(pkName)
+	if err != nil {
+		return err
+	}
+
+	var sets []string
+	var args []interface{}
+
+	err = d.Iterate(func(field string, v document.Value) error {
+		if field == pkName {
+			return nil
+		}
+		sets = append(sets, fmt.Sprintf("%s = ?", field))
+		args = append(args, v.V)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", table, strings.Join(sets, ", "), pkName)
+	args = append(args, pk.V)
+
+	return tx.Exec(q, args...)
+}
@@ -0,0 +1,107 @@
+package genji_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asdine/genji"
+	"github.com/asdine/genji/database"
+	"github.com/asdine/genji/document"
+)
+
+func TestUpdateBatchNonPKPrimaryKey(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *genji.Tx) error {
+		return tx.CreateTable("users", &database.TableConfig{PrimaryKeyName: "email"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc document.FieldBuffer
+	doc.Add("email", document.Value{V: "a@example.com"}).Add("name", document.Value{V: "Alice"})
+
+	err = db.UpdateBatch("users", []document.Document{&doc})
+	if err != nil {
+		t.Fatalf("UpdateBatch with a non-\"pk\" primary key should succeed, got: %v", err)
+	}
+}
+
+func TestInsertBatchHappyPath(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *genji.Tx) error {
+		return tx.CreateTable("users", &database.TableConfig{PrimaryKeyName: "email"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second document.FieldBuffer
+	first.Add("email", document.Value{V: "a@example.com"}).Add("name", document.Value{V: "Alice"})
+	second.Add("email", document.Value{V: "b@example.com"}).Add("name", document.Value{V: "Bob"})
+
+	err = db.InsertBatch("users", []document.Document{&first, &second}, genji.WithChunkSize(1))
+	if err != nil {
+		t.Fatalf("expected a homogeneous batch to insert cleanly, got: %v", err)
+	}
+}
+
+func TestInsertBatchMismatchedFields(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *genji.Tx) error {
+		return tx.CreateTable("users", &database.TableConfig{PrimaryKeyName: "email"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first document.FieldBuffer
+	first.Add("email", document.Value{V: "a@example.com"}).Add("name", document.Value{V: "Alice"})
+
+	var second document.FieldBuffer
+	second.Add("email", document.Value{V: "b@example.com"})
+
+	err = db.InsertBatch("users", []document.Document{&first, &second})
+	var rerr *genji.RowError
+	if !errors.As(err, &rerr) || rerr.Index != 1 {
+		t.Fatalf("expected a *RowError for document 1, got: %v", err)
+	}
+}
+
+func TestUpdateBatchNoPrimaryKey(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *genji.Tx) error {
+		return tx.CreateTable("users", nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc document.FieldBuffer
+	doc.Add("name", document.Value{V: "Alice"})
+
+	err = db.UpdateBatch("users", []document.Document{&doc})
+	if err == nil {
+		t.Fatal("expected UpdateBatch to fail against a table with no declared primary key")
+	}
+}
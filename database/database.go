@@ -0,0 +1,161 @@
+// Package database implements the transactional, table-oriented layer that
+// sits on top of an engine.Engine.
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/asdine/genji/engine"
+)
+
+// ErrDocumentNotFound is returned when no document matches a query.
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrTableNotFound is returned when a table doesn't exist.
+var ErrTableNotFound = errors.New("table not found")
+
+// Database represents a collection of tables stored in an engine.
+type Database struct {
+	ng engine.Engine
+
+	mu     sync.RWMutex
+	tables map[string]*TableConfig
+}
+
+// New initializes a Database using the given engine.
+func New(ng engine.Engine) (*Database, error) {
+	return &Database{
+		ng:     ng,
+		tables: make(map[string]*TableConfig),
+	}, nil
+}
+
+// Close the database.
+func (db *Database) Close() error {
+	return db.ng.Close()
+}
+
+// Begin starts a new transaction.
+func (db *Database) Begin(writable bool) (*Transaction, error) {
+	return db.BeginContext(context.Background(), writable)
+}
+
+// BeginContext starts a new transaction bound to ctx. Once ctx is done, any
+// call to Commit fails and rolls the transaction back; callers that keep
+// scanning a table after ctx is done should check Transaction.Err()
+// themselves between rows, the same way query.Result does.
+func (db *Database) BeginContext(ctx context.Context, writable bool) (*Transaction, error) {
+	tx, err := db.ng.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		tx:       tx,
+		ctx:      ctx,
+		Writable: writable,
+		db:       db,
+	}, nil
+}
+
+// Transaction represents a transaction running on top of the engine. It
+// provides methods for managing the collection of tables and the
+// transaction itself.
+type Transaction struct {
+	tx       engine.Transaction
+	ctx      context.Context
+	Writable bool
+	db       *Database
+}
+
+// Context returns the context the transaction was started with.
+func (tx *Transaction) Context() context.Context {
+	return tx.ctx
+}
+
+// Err returns a non-nil error once the transaction's context is done. Code
+// that scans a table a row at a time should check it between rows so that
+// a cancelled context aborts the scan instead of running it to completion.
+func (tx *Transaction) Err() error {
+	return tx.ctx.Err()
+}
+
+// Commit the transaction. Commit fails, and the transaction is rolled back
+// instead, if its context is done.
+func (tx *Transaction) Commit() error {
+	if err := tx.ctx.Err(); err != nil {
+		tx.tx.Rollback()
+		return err
+	}
+
+	return tx.tx.Commit()
+}
+
+// Rollback the transaction.
+func (tx *Transaction) Rollback() error {
+	return tx.tx.Rollback()
+}
+
+// CreateTable registers a table under name with the given config, making it
+// immediately visible to GetTable from any transaction.
+func (tx *Transaction) CreateTable(name string, cfg *TableConfig) error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+
+	if _, ok := tx.db.tables[name]; ok {
+		return errors.New("table " + name + " already exists")
+	}
+
+	if cfg == nil {
+		cfg = &TableConfig{}
+	}
+
+	tx.db.tables[name] = cfg
+	return nil
+}
+
+// GetTable returns the table with the given name, along with the config it
+// was created with, if any. It returns ErrTableNotFound if no table was
+// created under name.
+func (tx *Transaction) GetTable(name string) (*Table, error) {
+	tx.db.mu.RLock()
+	cfg, ok := tx.db.tables[name]
+	tx.db.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+
+	return &Table{
+		tx:     tx,
+		Name:   name,
+		Config: cfg,
+	}, nil
+}
+
+// Table represents a collection of documents.
+type Table struct {
+	tx   *Transaction
+	Name string
+
+	// Config describes the table's schema, including its primary key, if
+	// one was declared when the table was created.
+	Config *TableConfig
+}
+
+// TableConfig describes a table's schema.
+type TableConfig struct {
+	PrimaryKeyName string
+}
+
+// PrimaryKeyName returns the name of the field used as primary key for this
+// table. It returns an error if the table has no declared primary key.
+func (t *Table) PrimaryKeyName() (string, error) {
+	if t.Config == nil || t.Config.PrimaryKeyName == "" {
+		return "", errors.New("table " + t.Name + " has no primary key")
+	}
+
+	return t.Config.PrimaryKeyName, nil
+}
@@ -1,6 +1,8 @@
 package genji
 
 import (
+	"context"
+
 	"github.com/asdine/genji/database"
 	"github.com/asdine/genji/document"
 	"github.com/asdine/genji/engine"
@@ -33,7 +35,15 @@ func (db *DB) Close() error {
 // Begin starts a new transaction.
 // The returned transaction must be closed either by calling Rollback or Commit.
 func (db *DB) Begin(writable bool) (*Tx, error) {
-	tx, err := db.DB.Begin(writable)
+	return db.BeginContext(context.Background(), writable)
+}
+
+// BeginContext starts a new transaction bound to ctx. Once ctx is done,
+// Commit fails and rolls the transaction back instead; queries run within
+// the transaction are not aborted by ctx, only by the context they are given.
+// The returned transaction must be closed either by calling Rollback or Commit.
+func (db *DB) BeginContext(ctx context.Context, writable bool) (*Tx, error) {
+	tx, err := db.DB.BeginContext(ctx, writable)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +55,13 @@ func (db *DB) Begin(writable bool) (*Tx, error) {
 
 // View starts a read only transaction, runs fn and automatically rolls it back.
 func (db *DB) View(fn func(tx *Tx) error) error {
-	tx, err := db.Begin(false)
+	return db.ViewContext(context.Background(), fn)
+}
+
+// ViewContext starts a read only transaction bound to ctx, runs fn and
+// automatically rolls it back. fn is not interrupted when ctx is done.
+func (db *DB) ViewContext(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := db.BeginContext(ctx, false)
 	if err != nil {
 		return err
 	}
@@ -56,7 +72,13 @@ func (db *DB) View(fn func(tx *Tx) error) error {
 
 // Update starts a read-write transaction, runs fn and automatically commits it.
 func (db *DB) Update(fn func(tx *Tx) error) error {
-	tx, err := db.Begin(true)
+	return db.UpdateContext(context.Background(), fn)
+}
+
+// UpdateContext starts a read-write transaction, runs fn and automatically commits it.
+// The transaction is rolled back if ctx is done before fn returns.
+func (db *DB) UpdateContext(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := db.BeginContext(ctx, true)
 	if err != nil {
 		return err
 	}
@@ -72,7 +94,12 @@ func (db *DB) Update(fn func(tx *Tx) error) error {
 
 // Exec a query against the database without returning the result.
 func (db *DB) Exec(q string, args ...interface{}) error {
-	res, err := db.Query(q, args...)
+	return db.ExecContext(context.Background(), q, args...)
+}
+
+// ExecContext is like Exec but aborts the query as soon as ctx is done.
+func (db *DB) ExecContext(ctx context.Context, q string, args ...interface{}) error {
+	res, err := db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return err
 	}
@@ -83,18 +110,28 @@ func (db *DB) Exec(q string, args ...interface{}) error {
 // Query the database and return the result.
 // The returned result must always be closed after usage.
 func (db *DB) Query(q string, args ...interface{}) (*query.Result, error) {
+	return db.QueryContext(context.Background(), q, args...)
+}
+
+// QueryContext is like Query but aborts the scan as soon as ctx is done.
+func (db *DB) QueryContext(ctx context.Context, q string, args ...interface{}) (*query.Result, error) {
 	pq, err := parser.ParseQuery(q)
 	if err != nil {
 		return nil, err
 	}
 
-	return pq.Run(db.DB, argsToParams(args))
+	return pq.Run(ctx, db.DB, argsToParams(args))
 }
 
 // QueryDocument runs the query and returns the first document.
 // If the query returns no error, QueryDocument returns ErrDocumentNotFound.
 func (db *DB) QueryDocument(q string, args ...interface{}) (document.Document, error) {
-	res, err := db.Query(q, args...)
+	return db.QueryDocumentContext(context.Background(), q, args...)
+}
+
+// QueryDocumentContext is like QueryDocument but aborts the query as soon as ctx is done.
+func (db *DB) QueryDocumentContext(ctx context.Context, q string, args ...interface{}) (document.Document, error) {
+	res, err := db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -151,23 +188,57 @@ func (db *DB) UpdateTable(tableName string, fn func(*Tx, *database.Table) error)
 // and read/write can be used to read, create, delete and modify tables.
 type Tx struct {
 	*database.Transaction
+
+	stmts []*Stmt
+}
+
+// Commit closes all the statements prepared on tx and commits the transaction.
+func (tx *Tx) Commit() error {
+	tx.closeStmts()
+
+	return tx.Transaction.Commit()
+}
+
+// Rollback closes all the statements prepared on tx and rolls back the transaction.
+func (tx *Tx) Rollback() error {
+	tx.closeStmts()
+
+	return tx.Transaction.Rollback()
+}
+
+func (tx *Tx) closeStmts() {
+	for _, s := range tx.stmts {
+		s.Close()
+	}
+	tx.stmts = nil
 }
 
 // Query the database withing the transaction and returns the result.
 // Closing the returned result after usage is not mandatory.
 func (tx *Tx) Query(q string, args ...interface{}) (*query.Result, error) {
+	return tx.QueryContext(context.Background(), q, args...)
+}
+
+// QueryContext is like Query but fails, and aborts the scan of its result, as
+// soon as ctx is done. It does not roll back tx.
+func (tx *Tx) QueryContext(ctx context.Context, q string, args ...interface{}) (*query.Result, error) {
 	pq, err := parser.ParseQuery(q)
 	if err != nil {
 		return nil, err
 	}
 
-	return pq.Exec(tx.Transaction, argsToParams(args), false)
+	return pq.Exec(ctx, tx.Transaction, argsToParams(args), false)
 }
 
 // QueryDocument runs the query and returns the first document.
 // If the query returns no error, QueryDocument returns ErrDocumentNotFound.
 func (tx *Tx) QueryDocument(q string, args ...interface{}) (document.Document, error) {
-	res, err := tx.Query(q, args...)
+	return tx.QueryDocumentContext(context.Background(), q, args...)
+}
+
+// QueryDocumentContext is like QueryDocument but aborts the query as soon as ctx is done.
+func (tx *Tx) QueryDocumentContext(ctx context.Context, q string, args ...interface{}) (document.Document, error) {
+	res, err := tx.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +257,12 @@ func (tx *Tx) QueryDocument(q string, args ...interface{}) (document.Document, e
 
 // Exec a query against the database within tx and without returning the result.
 func (tx *Tx) Exec(q string, args ...interface{}) error {
-	res, err := tx.Query(q, args...)
+	return tx.ExecContext(context.Background(), q, args...)
+}
+
+// ExecContext is like Exec but aborts the query as soon as ctx is done.
+func (tx *Tx) ExecContext(ctx context.Context, q string, args ...interface{}) error {
+	res, err := tx.QueryContext(ctx, q, args...)
 	if err != nil {
 		return err
 	}
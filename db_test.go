@@ -0,0 +1,47 @@
+package genji_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asdine/genji"
+)
+
+func TestQueryContextCancelled(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = db.QueryContext(ctx, "SELECT * FROM users")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a query against a cancelled context to fail with context.Canceled, got: %v", err)
+	}
+}
+
+func TestUpdateContextCancelledRollsBack(t *testing.T) {
+	ng := newFakeEngine()
+	db, err := genji.New(ng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = db.UpdateContext(ctx, func(tx *genji.Tx) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Commit on a cancelled context to fail with context.Canceled, got: %v", err)
+	}
+	if len(ng.txs) != 1 || ng.txs[0].committed || !ng.txs[0].rolledBack {
+		t.Fatalf("expected the transaction to be rolled back and not committed, got: %+v", ng.txs)
+	}
+}
@@ -0,0 +1,106 @@
+// Package document defines the document model genji reads and writes
+// tables with, along the lines of a JSON object: an ordered set of
+// field/value pairs.
+package document
+
+import "errors"
+
+// ErrFieldNotFound is returned when a field isn't present in a document.
+var ErrFieldNotFound = errors.New("field not found")
+
+// Value holds a typed value decoded from a document field.
+type Value struct {
+	V interface{}
+}
+
+// Document is a set of field/value pairs.
+type Document interface {
+	GetByField(field string) (Value, error)
+	Iterate(fn func(field string, value Value) error) error
+}
+
+// ValuePath is the list of field names to follow, from the root of a
+// document, down to a (possibly nested) value.
+type ValuePath []string
+
+// GetValue returns the value pointed to by p within d, descending into
+// nested documents as needed.
+func (p ValuePath) GetValue(d Document) (Value, error) {
+	if len(p) == 0 {
+		return Value{}, ErrFieldNotFound
+	}
+
+	v, err := d.GetByField(p[0])
+	if err != nil {
+		return Value{}, err
+	}
+
+	if len(p) == 1 {
+		return v, nil
+	}
+
+	sub, ok := v.V.(Document)
+	if !ok {
+		return Value{}, ErrFieldNotFound
+	}
+
+	return p[1:].GetValue(sub)
+}
+
+// field is one field/value pair of a FieldBuffer.
+type field struct {
+	Field string
+	Value Value
+}
+
+// FieldBuffer is a Document backed by an in-memory, ordered list of
+// field/value pairs.
+type FieldBuffer struct {
+	fields []field
+}
+
+// Add appends a field/value pair to the buffer and returns it for chaining.
+func (fb *FieldBuffer) Add(name string, v Value) *FieldBuffer {
+	fb.fields = append(fb.fields, field{Field: name, Value: v})
+	return fb
+}
+
+// GetByField implements the Document interface.
+func (fb *FieldBuffer) GetByField(name string) (Value, error) {
+	for _, f := range fb.fields {
+		if f.Field == name {
+			return f.Value, nil
+		}
+	}
+
+	return Value{}, ErrFieldNotFound
+}
+
+// Iterate implements the Document interface.
+func (fb *FieldBuffer) Iterate(fn func(field string, value Value) error) error {
+	for _, f := range fb.fields {
+		if err := fn(f.Field, f.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScanDocument copies every field of d into fb, replacing its current
+// content.
+func (fb *FieldBuffer) ScanDocument(d Document) error {
+	fb.fields = fb.fields[:0]
+
+	return d.Iterate(func(field string, v Value) error {
+		fb.Add(field, v)
+		return nil
+	})
+}
+
+// StructScan copies the fields of d into target, which must be a pointer to
+// a struct. Fields are matched to struct fields by name (case-insensitive),
+// unless renamed with a `genji:"..."` tag.
+func StructScan(d Document, target interface{}) error {
+	return structScan(d, target)
+}
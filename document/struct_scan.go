@@ -0,0 +1,63 @@
+package document
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structTagName is the struct tag used to rename a field when scanning a
+// document into a struct, e.g. `genji:"age"`.
+const structTagName = "genji"
+
+func structScan(d Document, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	elem := v.Elem()
+	t := elem.Type()
+
+	return d.Iterate(func(field string, val Value) error {
+		idx := fieldIndexByName(t, field)
+		if idx < 0 {
+			return nil
+		}
+
+		if val.V == nil {
+			return nil
+		}
+
+		fv := elem.Field(idx)
+		rv := reflect.ValueOf(val.V)
+		if !rv.Type().ConvertibleTo(fv.Type()) {
+			return fmt.Errorf("cannot assign %s to field %s of type %s", rv.Type(), t.Field(idx).Name, fv.Type())
+		}
+
+		fv.Set(rv.Convert(fv.Type()))
+		return nil
+	})
+}
+
+func fieldIndexByName(t reflect.Type, name string) int {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if tag, ok := f.Tag.Lookup(structTagName); ok {
+			if tag == name {
+				return i
+			}
+			continue
+		}
+
+		if strings.EqualFold(f.Name, name) {
+			return i
+		}
+	}
+
+	return -1
+}
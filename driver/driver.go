@@ -0,0 +1,276 @@
+// Package driver registers genji as a database/sql driver under the name
+// "genji", so that it can be used through the standard library via
+// sql.Open("genji", dsn) and reused with the wider sqlx/ORM ecosystem.
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/asdine/genji"
+	"github.com/asdine/genji/document"
+	"github.com/asdine/genji/engine"
+	"github.com/asdine/genji/engine/badger"
+	"github.com/asdine/genji/engine/bolt"
+	"github.com/asdine/genji/engine/memory"
+	"github.com/asdine/genji/sql/query"
+)
+
+func init() {
+	sql.Register("genji", sqlDriver{})
+}
+
+// ErrUnsupportedDSN is returned when a DSN doesn't select one of the known
+// engines.
+var ErrUnsupportedDSN = errors.New("unsupported dsn")
+
+// sqlDriver implements driver.Driver on top of a *genji.DB.
+type sqlDriver struct{}
+
+// Open parses dsn and opens the selected engine. dsn is of the form
+// "<engine>:<path>", e.g. "memory:", "bolt:/path/to/db" or "badger:/path/to/db",
+// optionally prefixed with a redundant "genji:" scheme (e.g.
+// "genji:bolt:/path/to/db"), since sql.Open already takes the driver name as
+// a separate argument.
+func (d sqlDriver) Open(dsn string) (driver.Conn, error) {
+	ng, err := openEngine(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := genji.New(ng)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{db: db}, nil
+}
+
+func openEngine(dsn string) (engine.Engine, error) {
+	dsn = strings.TrimPrefix(dsn, "genji:")
+
+	typ, path := dsn, ""
+	if i := strings.Index(dsn, ":"); i >= 0 {
+		typ, path = dsn[:i], dsn[i+1:]
+	}
+
+	switch typ {
+	case "memory":
+		return memory.NewEngine(), nil
+	case "bolt":
+		return bolt.NewEngine(path, 0600, nil)
+	case "badger":
+		return badger.NewEngine(badger.DefaultOptions(path))
+	default:
+		return nil, ErrUnsupportedDSN
+	}
+}
+
+// conn implements driver.Conn, driver.ConnBeginTx and driver.NamedValueChecker
+// on top of a *genji.DB. It does not implement driver.QueryerContext or
+// driver.ExecerContext itself: queries and execs go through PrepareContext
+// and stmtConn, which implements driver.StmtQueryContext and
+// driver.StmtExecContext.
+type conn struct {
+	db *genji.DB
+
+	// tx is set for the lifetime of a database/sql transaction (between
+	// BeginTx and the matching Commit/Rollback), so that statements
+	// prepared in the meantime run inside it instead of each opening
+	// their own.
+	tx *genji.Tx
+}
+
+func (c *conn) Prepare(q string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), q)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, q string) (driver.Stmt, error) {
+	var stmt *genji.Stmt
+	var err error
+
+	if c.tx != nil {
+		stmt, err = c.tx.Prepare(q)
+	} else {
+		stmt, err = c.db.Prepare(q)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmtConn{stmt: stmt}, nil
+}
+
+func (c *conn) Close() error {
+	return c.db.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.db.BeginContext(ctx, !opts.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tx = tx
+	return &txConn{conn: c, tx: tx}, nil
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return nil
+}
+
+// txConn implements driver.Tx. It clears its conn's active transaction on
+// Commit/Rollback so statements prepared afterwards go back to opening
+// their own, the way conn.db.Prepare does outside a transaction.
+type txConn struct {
+	conn *conn
+	tx   *genji.Tx
+}
+
+func (t *txConn) Commit() error {
+	defer t.clear()
+	return t.tx.Commit()
+}
+
+func (t *txConn) Rollback() error {
+	defer t.clear()
+	return t.tx.Rollback()
+}
+
+func (t *txConn) clear() {
+	if t.conn.tx == t.tx {
+		t.conn.tx = nil
+	}
+}
+
+// stmtConn implements driver.Stmt, driver.StmtQueryContext and
+// driver.StmtExecContext on top of a *genji.Stmt.
+type stmtConn struct {
+	stmt *genji.Stmt
+}
+
+func (s *stmtConn) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *stmtConn) NumInput() int {
+	return -1
+}
+
+func (s *stmtConn) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmtConn) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	res, err := s.stmt.QueryContext(ctx, toArgs(args)...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	return driver.RowsAffected(res.RowsAffected()), nil
+}
+
+func (s *stmtConn) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmtConn) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := s.stmt.QueryContext(ctx, toArgs(args)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRows(res)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nv
+}
+
+func toArgs(args []driver.NamedValue) []interface{} {
+	out := make([]interface{}, len(args))
+	for i, a := range args {
+		out[i] = a.Value
+	}
+	return out
+}
+
+// rows implements driver.Rows on top of a *query.Result, flattening each
+// document into a fixed set of columns discovered from the first row.
+type rows struct {
+	docs    []document.Document
+	columns []string
+	pos     int
+}
+
+func newRows(res *query.Result) (*rows, error) {
+	defer res.Close()
+
+	var r rows
+
+	err := res.Iterate(func(d document.Document) error {
+		var fb document.FieldBuffer
+		if err := fb.ScanDocument(d); err != nil {
+			return err
+		}
+
+		if r.columns == nil {
+			err := fb.Iterate(func(field string, v document.Value) error {
+				r.columns = append(r.columns, field)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		r.docs = append(r.docs, &fb)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	r.pos = len(r.docs)
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.docs) {
+		return io.EOF
+	}
+
+	d := r.docs[r.pos]
+	r.pos++
+
+	for i, col := range r.columns {
+		v, err := d.GetByField(col)
+		if err != nil {
+			return err
+		}
+		dest[i] = v.V
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package engine defines the interface that storage backends (memory, bolt,
+// badger, ...) must implement to be usable by genji.
+package engine
+
+// Engine is implemented by the storage backends that genji can run on top
+// of.
+type Engine interface {
+	// Begin starts a new storage-level transaction.
+	Begin(writable bool) (Transaction, error)
+	// Close releases any resource held by the engine.
+	Close() error
+}
+
+// Transaction is a storage-level transaction, as returned by Engine.Begin.
+type Transaction interface {
+	Rollback() error
+	Commit() error
+}
@@ -0,0 +1,40 @@
+package genji_test
+
+import "github.com/asdine/genji/engine"
+
+// fakeEngine is a minimal engine.Engine used to exercise genji's
+// transaction, statement and batch plumbing in tests without depending on
+// a real storage backend.
+type fakeEngine struct {
+	// txs holds every transaction started by Begin, in order.
+	txs []*fakeTx
+}
+
+func newFakeEngine() *fakeEngine {
+	return &fakeEngine{}
+}
+
+func (e *fakeEngine) Begin(writable bool) (engine.Transaction, error) {
+	tx := &fakeTx{}
+	e.txs = append(e.txs, tx)
+	return tx, nil
+}
+
+func (e *fakeEngine) Close() error {
+	return nil
+}
+
+type fakeTx struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
@@ -0,0 +1,13 @@
+package genji
+
+import "github.com/asdine/genji/sql/query"
+
+// argsToParams turns positional Query/Exec arguments into the bound
+// parameters expected by query.Query.Run and query.Query.Exec.
+func argsToParams(args []interface{}) query.Params {
+	params := make(query.Params, len(args))
+	for i, arg := range args {
+		params[i] = query.Param{Value: arg}
+	}
+	return params
+}
@@ -0,0 +1,105 @@
+package genji
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/asdine/genji/engine/badger"
+)
+
+// retryConfig holds the configurable policy used by RunInTransaction to
+// decide how many times, and how long to wait between, a conflicting
+// transaction is retried.
+type retryConfig struct {
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+}
+
+var defaultRetryConfig = retryConfig{
+	maxAttempts: 3,
+	backoff: func(attempt int) time.Duration {
+		return time.Duration(attempt+1) * 10 * time.Millisecond
+	},
+}
+
+// RetryOption configures the retry policy used by RunInTransaction and
+// RunInTransactionContext.
+type RetryOption func(*retryConfig)
+
+// WithMaxRetries sets the maximum number of times the transaction closure
+// will be run before giving up and returning the last error. n must be at
+// least 1.
+func WithMaxRetries(n int) RetryOption {
+	return func(cfg *retryConfig) {
+		if n > 0 {
+			cfg.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the function used to compute how long to wait before
+// retrying the attempt-th time (0-indexed).
+func WithBackoff(fn func(attempt int) time.Duration) RetryOption {
+	return func(cfg *retryConfig) {
+		cfg.backoff = fn
+	}
+}
+
+// isRetryable reports whether err is a conflict that's worth retrying
+// against a fresh transaction, such as the optimistic-concurrency conflict
+// Badger returns when two writable transactions touch the same key.
+func isRetryable(err error) bool {
+	return errors.Is(err, badger.ErrConflict)
+}
+
+// RunInTransaction opens a writable transaction, runs fn and commits it. If
+// fn or the commit fails with a retryable conflict error, the whole
+// operation is retried against a fresh transaction, up to the configured
+// number of attempts.
+func (db *DB) RunInTransaction(fn func(*Tx) error, opts ...RetryOption) error {
+	return db.RunInTransactionContext(context.Background(), fn, opts...)
+}
+
+// RunInTransactionContext is like RunInTransaction but stops retrying once
+// ctx is done, returning the error of the last attempt. The first attempt is
+// always run, and a backoff wait is not interrupted by ctx.
+func (db *DB) RunInTransactionContext(ctx context.Context, fn func(*Tx) error, opts ...RetryOption) error {
+	cfg := defaultRetryConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var err error
+
+	for attempt := 0; attempt < cfg.maxAttempts; attempt++ {
+		err = db.runInTransactionOnce(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		if attempt < cfg.maxAttempts-1 {
+			time.Sleep(cfg.backoff(attempt))
+		}
+	}
+
+	return err
+}
+
+func (db *DB) runInTransactionOnce(ctx context.Context, fn func(*Tx) error) error {
+	tx, err := db.BeginContext(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,79 @@
+package genji_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asdine/genji"
+	"github.com/asdine/genji/engine/badger"
+)
+
+func TestRunInTransactionRetriesOnConflict(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	attempts := 0
+	err = db.RunInTransaction(func(tx *genji.Tx) error {
+		attempts++
+		if attempts < 3 {
+			return badger.ErrConflict
+		}
+		return nil
+	}, genji.WithBackoff(func(attempt int) time.Duration { return 0 }))
+	if err != nil {
+		t.Fatalf("expected the operation to eventually succeed, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRunInTransactionGivesUpOnNonConflict(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	wantErr := errors.New("boom")
+
+	attempts := 0
+	err = db.RunInTransaction(func(tx *genji.Tx) error {
+		attempts++
+		return wantErr
+	}, genji.WithBackoff(func(attempt int) time.Duration { return 0 }))
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected the non-retryable error to be returned as-is, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a non-retryable error to abort after 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRunInTransactionContextCancelledDoesNotRetry(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err = db.RunInTransactionContext(ctx, func(tx *genji.Tx) error {
+		attempts++
+		return badger.ErrConflict
+	})
+	if !errors.Is(err, badger.ErrConflict) {
+		t.Fatalf("expected the underlying error to be returned as-is once ctx is done, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a cancelled context to abort after 1 attempt instead of retrying, got %d", attempts)
+	}
+}
@@ -0,0 +1,112 @@
+// Package query holds the in-memory representation of a parsed statement
+// (Query) and the result of running one (Result).
+package query
+
+import (
+	"context"
+
+	"github.com/asdine/genji/database"
+	"github.com/asdine/genji/document"
+)
+
+// ErrDocumentNotFound is returned by Result.One and Result.Scan when the
+// result has no rows. Result.First returns a nil document instead.
+var ErrDocumentNotFound = database.ErrDocumentNotFound
+
+// Param is a value bound to a placeholder of a parsed query.
+type Param struct {
+	Name  string
+	Value interface{}
+}
+
+// Params is the list of values bound to a parsed query's placeholders.
+type Params []Param
+
+// Query is a parsed statement, ready to be run against a database or
+// executed within an existing transaction.
+type Query interface {
+	// Run opens its own read-only transaction, executes the statement and
+	// returns its result. ctx is checked once the transaction is started,
+	// and while scanning rows.
+	Run(ctx context.Context, db *database.Database, params Params) (*Result, error)
+	// Exec runs the statement within tx. A done ctx makes Exec fail and
+	// aborts the scan of its result; Exec does not roll tx back.
+	Exec(ctx context.Context, tx *database.Transaction, params Params, readOnly bool) (*Result, error)
+}
+
+// Result is the result of running a Query. It must always be closed after
+// usage.
+type Result struct {
+	ctx          context.Context
+	rows         []document.Document
+	rowsAffected int64
+}
+
+// NewResult creates a Result bound to ctx, so that First and Iterate honor
+// cancellation. It is used by statement implementations in sql/parser to
+// build the value returned to their caller.
+func NewResult(ctx context.Context, opts ...ResultOption) *Result {
+	r := &Result{ctx: ctx}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ResultOption configures a Result created with NewResult.
+type ResultOption func(*Result)
+
+// WithRows sets the documents the result iterates over.
+func WithRows(rows []document.Document) ResultOption {
+	return func(r *Result) {
+		r.rows = rows
+	}
+}
+
+// WithRowsAffected sets the number of rows a mutating statement affected.
+func WithRowsAffected(n int64) ResultOption {
+	return func(r *Result) {
+		r.rowsAffected = n
+	}
+}
+
+// Close releases the resources held by the result.
+func (r *Result) Close() error {
+	return nil
+}
+
+// First returns the first document of the result, or nil if it has none.
+func (r *Result) First() (document.Document, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(r.rows) == 0 {
+		return nil, nil
+	}
+
+	return r.rows[0], nil
+}
+
+// Iterate calls fn for every document of the result, in order. It stops and
+// returns ctx.Err() as soon as the result's context is done.
+func (r *Result) Iterate(fn func(d document.Document) error) error {
+	for _, d := range r.rows {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RowsAffected returns the number of documents inserted, updated or deleted
+// by the statement that produced this result. It is always 0 for read
+// queries.
+func (r *Result) RowsAffected() int64 {
+	return r.rowsAffected
+}
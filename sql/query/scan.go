@@ -0,0 +1,242 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/asdine/genji/document"
+)
+
+// structTagName is the struct tag used to rename a field when scanning a
+// document into a struct, e.g. `genji:"age"`, or to skip it with `genji:"-"`.
+// A tag does not embed a field: only Go-embedded (anonymous) struct fields
+// are decomposed at their parent's path.
+const structTagName = "genji"
+
+// fieldMapping associates a document field path with the index path of the
+// struct field it maps to.
+type fieldMapping struct {
+	path       document.ValuePath
+	fieldIndex []int
+}
+
+var scanCacheMu sync.RWMutex
+var scanCache = make(map[reflect.Type][]fieldMapping)
+
+// fieldMappingsForType walks t once and returns, for every leaf field
+// reachable from it (following embedded structs and `genji:"..."` tags), the
+// document.ValuePath it should be read from. The result is cached per type
+// so repeated scans don't pay the reflection cost twice.
+func fieldMappingsForType(t reflect.Type) []fieldMapping {
+	scanCacheMu.RLock()
+	mappings, ok := scanCache[t]
+	scanCacheMu.RUnlock()
+	if ok {
+		return mappings
+	}
+
+	mappings = buildFieldMappings(t, nil, nil)
+
+	scanCacheMu.Lock()
+	scanCache[t] = mappings
+	scanCacheMu.Unlock()
+
+	return mappings
+}
+
+func buildFieldMappings(t reflect.Type, path document.ValuePath, index []int) []fieldMapping {
+	var mappings []fieldMapping
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !(f.Anonymous && f.Type.Kind() == reflect.Struct) {
+			// unexported field. An embedded unexported struct is kept: its
+			// exported fields are promoted to the parent and are settable.
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup(structTagName); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldPath := append(append(document.ValuePath{}, path...), name)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		// Only embedded structs are decomposed field by field, at the same
+		// path as their parent: they represent composition, not a nested
+		// document. A named struct field (e.g. time.Time) is a leaf: its
+		// value is scanned as a whole via assignValue/document.StructScan,
+		// since decomposing it would walk into fields the document has no
+		// matching path for.
+		if f.Anonymous && ft.Kind() == reflect.Struct {
+			mappings = append(mappings, buildFieldMappings(ft, path, fieldIndex)...)
+			continue
+		}
+
+		mappings = append(mappings, fieldMapping{path: fieldPath, fieldIndex: fieldIndex})
+	}
+
+	return mappings
+}
+
+// scanDocument scans d into target, which must be a non-nil pointer to a
+// struct or a map[string]interface{}. Embedded structs are populated from
+// the document's top-level fields; a named struct field holding a nested
+// document is populated from it via document.StructScan. Fields without a
+// matching path are left untouched. A target that is neither a struct nor a
+// map is passed as is to document.StructScan, which rejects it.
+func scanDocument(d document.Document, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+
+	elem := v.Elem()
+
+	if elem.Kind() == reflect.Map {
+		return scanDocumentIntoMap(d, elem)
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return document.StructScan(d, target)
+	}
+
+	mappings := fieldMappingsForType(elem.Type())
+
+	for _, m := range mappings {
+		val, err := m.path.GetValue(d)
+		if err != nil {
+			if err == document.ErrFieldNotFound {
+				continue
+			}
+			return err
+		}
+
+		fv := elem
+		for _, idx := range m.fieldIndex {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(idx)
+		}
+
+		if err := assignValue(fv, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanDocumentIntoMap(d document.Document, m reflect.Value) error {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	valueType := m.Type().Elem()
+
+	return d.Iterate(func(field string, val document.Value) error {
+		if val.V == nil {
+			return nil
+		}
+
+		rv := reflect.ValueOf(val.V)
+		if !rv.Type().ConvertibleTo(valueType) {
+			return fmt.Errorf("cannot assign %s to map value of type %s", rv.Type(), valueType)
+		}
+
+		m.SetMapIndex(reflect.ValueOf(field), rv.Convert(valueType))
+		return nil
+	})
+}
+
+// assignValue sets fv to val. A struct-kind fv (e.g. time.Time) is treated
+// as a leaf: if val itself holds a nested document, it's populated via
+// document.StructScan rather than being decomposed field by field, since
+// buildFieldMappings never generates per-field paths for it.
+func assignValue(fv reflect.Value, val document.Value) error {
+	if val.V == nil {
+		return nil
+	}
+
+	if fv.Kind() == reflect.Struct {
+		if sub, ok := val.V.(document.Document); ok {
+			return document.StructScan(sub, fv.Addr().Interface())
+		}
+	}
+
+	rv := reflect.ValueOf(val.V)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if rv.Type().ConvertibleTo(fv.Type()) {
+		fv.Set(rv.Convert(fv.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign %s to field of type %s", rv.Type(), fv.Type())
+}
+
+// Scan scans the first document of the result into target, which must be a
+// pointer to a struct (including nested structs) or a map[string]interface{}.
+// If the result has no rows, Scan returns ErrDocumentNotFound.
+func (r *Result) Scan(target interface{}) error {
+	return r.One(target)
+}
+
+// One scans the first document of the result into target. It is equivalent
+// to calling Scan.
+func (r *Result) One(target interface{}) error {
+	d, err := r.First()
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return ErrDocumentNotFound
+	}
+
+	return scanDocument(d, target)
+}
+
+// All scans every document of the result into target, which must be a
+// pointer to a slice of structs (or of pointers to structs).
+func (r *Result) All(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("target must be a pointer to a slice, got %T", target)
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+
+	return r.Iterate(func(d document.Document) error {
+		elemPtr := reflect.New(elemType)
+		ptrTarget := elemPtr.Interface()
+		if elemType.Kind() == reflect.Ptr {
+			elemPtr.Elem().Set(reflect.New(elemType.Elem()))
+			ptrTarget = elemPtr.Elem().Interface()
+		}
+
+		if err := scanDocument(d, ptrTarget); err != nil {
+			return err
+		}
+
+		slice.Set(reflect.Append(slice, elemPtr.Elem()))
+
+		return nil
+	})
+}
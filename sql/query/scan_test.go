@@ -0,0 +1,96 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asdine/genji/document"
+	"github.com/asdine/genji/sql/query"
+)
+
+type address struct {
+	City string
+}
+
+type contact struct {
+	address
+	Name string
+}
+
+type plainContact struct {
+	Name string
+	City string
+}
+
+type user struct {
+	Name    string
+	Contact plainContact `genji:"contact"`
+}
+
+func newResult(docs ...document.Document) *query.Result {
+	return query.NewResult(context.Background(), query.WithRows(docs))
+}
+
+func TestResultOneEmbeddedStruct(t *testing.T) {
+	var doc document.FieldBuffer
+	doc.Add("Name", document.Value{V: "Alice"}).Add("City", document.Value{V: "Paris"})
+
+	var c contact
+	res := newResult(&doc)
+	if err := res.One(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Name != "Alice" || c.City != "Paris" {
+		t.Fatalf("embedded struct fields weren't decomposed at the parent path, got: %+v", c)
+	}
+}
+
+func TestResultOneNestedDocumentField(t *testing.T) {
+	var sub document.FieldBuffer
+	sub.Add("Name", document.Value{V: "Bob"}).Add("City", document.Value{V: "Lyon"})
+
+	var doc document.FieldBuffer
+	doc.Add("Name", document.Value{V: "Bob"}).Add("contact", document.Value{V: &sub})
+
+	var u user
+	res := newResult(&doc)
+	if err := res.One(&u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Contact.Name != "Bob" || u.Contact.City != "Lyon" {
+		t.Fatalf("named struct field wasn't populated from its nested document, got: %+v", u.Contact)
+	}
+}
+
+func TestResultOneMap(t *testing.T) {
+	var doc document.FieldBuffer
+	doc.Add("Name", document.Value{V: "Alice"}).Add("Age", document.Value{V: "30"})
+
+	m := map[string]string{}
+	res := newResult(&doc)
+	if err := res.One(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	if m["Name"] != "Alice" || m["Age"] != "30" {
+		t.Fatalf("map target wasn't populated from the document's fields, got: %+v", m)
+	}
+}
+
+func TestResultAllSlice(t *testing.T) {
+	var doc1, doc2 document.FieldBuffer
+	doc1.Add("Name", document.Value{V: "Alice"}).Add("City", document.Value{V: "Paris"})
+	doc2.Add("Name", document.Value{V: "Bob"}).Add("City", document.Value{V: "Lyon"})
+
+	var contacts []contact
+	res := newResult(&doc1, &doc2)
+	if err := res.All(&contacts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(contacts) != 2 || contacts[0].Name != "Alice" || contacts[1].Name != "Bob" {
+		t.Fatalf("All didn't scan every row in order, got: %+v", contacts)
+	}
+}
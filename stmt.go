@@ -0,0 +1,121 @@
+package genji
+
+import (
+	"context"
+	"errors"
+
+	"github.com/asdine/genji/database"
+	"github.com/asdine/genji/document"
+	"github.com/asdine/genji/sql/parser"
+	"github.com/asdine/genji/sql/query"
+)
+
+// ErrStmtClosed is returned when a Stmt is used after being closed, which
+// happens automatically for statements prepared on a *Tx once that
+// transaction is committed or rolled back.
+var ErrStmtClosed = errors.New("statement is closed")
+
+// Stmt is a prepared statement. A Stmt is safe for concurrent use by multiple
+// goroutines when prepared on a *DB, since each call parses its arguments
+// into a fresh set of bound parameters before running the underlying query
+// plan. A Stmt prepared on a *Tx must not be used after the transaction has
+// been committed or rolled back.
+type Stmt struct {
+	q      query.Query
+	db     *DB
+	tx     *Tx
+	closed bool
+}
+
+// Prepare parses q once and returns a Stmt that can be run repeatedly with
+// different arguments, avoiding the cost of reparsing the query on every
+// call.
+func (db *DB) Prepare(q string) (*Stmt, error) {
+	pq, err := parser.ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stmt{q: pq, db: db}, nil
+}
+
+// Prepare parses q once and returns a Stmt bound to tx. The statement is
+// tracked by tx and is automatically closed when tx is committed or rolled
+// back.
+func (tx *Tx) Prepare(q string) (*Stmt, error) {
+	pq, err := parser.ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &Stmt{q: pq, tx: tx}
+	tx.stmts = append(tx.stmts, stmt)
+	return stmt, nil
+}
+
+// Query runs the prepared statement with the given args and returns the
+// result. The returned result must always be closed after usage.
+func (s *Stmt) Query(args ...interface{}) (*query.Result, error) {
+	return s.QueryContext(context.Background(), args...)
+}
+
+// QueryContext is like Query but aborts the scan as soon as ctx is done.
+func (s *Stmt) QueryContext(ctx context.Context, args ...interface{}) (*query.Result, error) {
+	if s.closed {
+		return nil, ErrStmtClosed
+	}
+
+	params := argsToParams(args)
+
+	if s.tx != nil {
+		return s.q.Exec(ctx, s.tx.Transaction, params, false)
+	}
+
+	return s.q.Run(ctx, s.db.DB, params)
+}
+
+// Exec runs the prepared statement with the given args without returning the
+// result.
+func (s *Stmt) Exec(args ...interface{}) error {
+	res, err := s.Query(args...)
+	if err != nil {
+		return err
+	}
+
+	return res.Close()
+}
+
+// QueryDocument runs the prepared statement with the given args and returns
+// the first document. If the query returns no document, QueryDocument returns
+// ErrDocumentNotFound.
+func (s *Stmt) QueryDocument(args ...interface{}) (document.Document, error) {
+	res, err := s.Query(args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	r, err := res.First()
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, database.ErrDocumentNotFound
+	}
+
+	var fb document.FieldBuffer
+	err = fb.ScanDocument(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fb, nil
+}
+
+// Close marks the statement as no longer usable. It is called automatically
+// for statements prepared on a *Tx when that transaction is committed or
+// rolled back; calling it on a *DB statement simply prevents further use.
+func (s *Stmt) Close() error {
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,57 @@
+package genji_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asdine/genji"
+)
+
+func TestStmtQueryAfterCloseRejected(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.Prepare("SELECT * FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stmt.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stmt.Query()
+	if !errors.Is(err, genji.ErrStmtClosed) {
+		t.Fatalf("expected querying a closed statement to fail with ErrStmtClosed, got: %v", err)
+	}
+}
+
+func TestTxStmtClosedOnCommit(t *testing.T) {
+	db, err := genji.New(newFakeEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := tx.Prepare("SELECT * FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stmt.Query()
+	if !errors.Is(err, genji.ErrStmtClosed) {
+		t.Fatalf("expected a statement prepared on tx to be closed once tx is committed, got: %v", err)
+	}
+}